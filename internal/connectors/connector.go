@@ -0,0 +1,47 @@
+package connectors
+
+import "context"
+
+// Connector dispatches the outbound leg of a transfer to an external payment
+// provider (bank rails, wallet-to-wallet, ...) instead of moving balance
+// between two internal accounts.
+type Connector interface {
+	InitiateTransfer(ctx context.Context, req InitiateTransferRequest) (*InitiateTransferResponse, error)
+	PollStatus(ctx context.Context, providerReference string) (*StatusResponse, error)
+	Cancel(ctx context.Context, providerReference string) error
+}
+
+// Status is the lifecycle state of an outbound transfer as reported by the
+// external provider.
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// Terminal reports whether the provider has reached a final state and
+// polling can stop.
+func (s Status) Terminal() bool {
+	return s == StatusSucceeded || s == StatusFailed
+}
+
+type InitiateTransferRequest struct {
+	ReferenceID   string
+	Amount        int
+	CurrencyCode  string
+	DestinationID string
+	Metadata      map[string]interface{}
+}
+
+type InitiateTransferResponse struct {
+	ProviderReference string
+	Status            Status
+}
+
+type StatusResponse struct {
+	ProviderReference string
+	Status            Status
+	FailureReason     string
+}
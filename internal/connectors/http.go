@@ -0,0 +1,87 @@
+package connectors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// HTTPConnectorConfig configures an HTTPConnector for one external provider.
+type HTTPConnectorConfig struct {
+	BaseURL string
+	APIKey  string
+	Client  *http.Client
+}
+
+// HTTPConnector dispatches outbound transfers to an external provider over a
+// simple REST API. It is a reference implementation; real providers will
+// have their own request/response shapes and should implement Connector
+// directly rather than extend this type.
+type HTTPConnector struct {
+	config HTTPConnectorConfig
+}
+
+func NewHTTPConnector(config HTTPConnectorConfig) *HTTPConnector {
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	return &HTTPConnector{config: config}
+}
+
+func (c *HTTPConnector) InitiateTransfer(ctx context.Context, req InitiateTransferRequest) (*InitiateTransferResponse, error) {
+	var resp InitiateTransferResponse
+	if err := c.do(ctx, http.MethodPost, "/transfers", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *HTTPConnector) PollStatus(ctx context.Context, providerReference string) (*StatusResponse, error) {
+	var resp StatusResponse
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/transfers/%s", providerReference), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *HTTPConnector) Cancel(ctx context.Context, providerReference string) error {
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("/transfers/%s/cancel", providerReference), nil, nil)
+}
+
+func (c *HTTPConnector) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reqBody *bytes.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("connectors: encoding request: %w", err)
+		}
+		reqBody = bytes.NewReader(encoded)
+	} else {
+		reqBody = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.config.BaseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("connectors: building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+
+	resp, err := c.config.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("connectors: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("connectors: request returned status %d", resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
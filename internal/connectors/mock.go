@@ -0,0 +1,50 @@
+package connectors
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// MockConnector immediately succeeds every transfer it's given. Intended for
+// local development and tests that exercise the connector dispatch path
+// without calling out to a real provider.
+type MockConnector struct {
+	mu      sync.Mutex
+	results map[string]Status
+}
+
+func NewMockConnector() *MockConnector {
+	return &MockConnector{results: make(map[string]Status)}
+}
+
+func (c *MockConnector) InitiateTransfer(ctx context.Context, req InitiateTransferRequest) (*InitiateTransferResponse, error) {
+	providerRef := uuid.NewString()
+
+	c.mu.Lock()
+	c.results[providerRef] = StatusSucceeded
+	c.mu.Unlock()
+
+	return &InitiateTransferResponse{ProviderReference: providerRef, Status: StatusSucceeded}, nil
+}
+
+func (c *MockConnector) PollStatus(ctx context.Context, providerReference string) (*StatusResponse, error) {
+	c.mu.Lock()
+	status, ok := c.results[providerReference]
+	c.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("connectors: unknown provider reference %q", providerReference)
+	}
+
+	return &StatusResponse{ProviderReference: providerReference, Status: status}, nil
+}
+
+func (c *MockConnector) Cancel(ctx context.Context, providerReference string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results[providerReference] = StatusFailed
+	return nil
+}
@@ -0,0 +1,25 @@
+package connectors
+
+import "fmt"
+
+// Registry resolves a Connector by the connector id stored on the
+// destination account.
+type Registry struct {
+	connectors map[string]Connector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{connectors: make(map[string]Connector)}
+}
+
+func (r *Registry) Register(connectorID string, connector Connector) {
+	r.connectors[connectorID] = connector
+}
+
+func (r *Registry) Resolve(connectorID string) (Connector, error) {
+	connector, ok := r.connectors[connectorID]
+	if !ok {
+		return nil, fmt.Errorf("connectors: no connector registered for id %q", connectorID)
+	}
+	return connector, nil
+}
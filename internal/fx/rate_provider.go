@@ -0,0 +1,131 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RateProvider quotes an exchange rate between two currencies that can be
+// locked for the duration of a workflow execution.
+type RateProvider interface {
+	Quote(ctx context.Context, baseCurrency, quoteCurrency string) (*Rate, error)
+}
+
+// Rate is a quoted exchange rate from BaseCurrency to QuoteCurrency, valid
+// until ExpiresAt.
+type Rate struct {
+	ProviderID    string
+	BaseCurrency  string
+	QuoteCurrency string
+	Rate          float64
+	QuotedAt      time.Time
+	ExpiresAt     time.Time
+}
+
+// InMemoryRateProvider serves fixed rates from a static table. It is intended
+// for local development and tests where calling out to a real provider isn't
+// desirable.
+type InMemoryRateProvider struct {
+	ProviderID string
+	Rates      map[string]float64 // keyed by "BASE/QUOTE", e.g. "USD/EUR"
+	TTL        time.Duration
+	now        func() time.Time
+}
+
+func NewInMemoryRateProvider(providerID string, rates map[string]float64, ttl time.Duration) *InMemoryRateProvider {
+	return &InMemoryRateProvider{
+		ProviderID: providerID,
+		Rates:      rates,
+		TTL:        ttl,
+		now:        time.Now,
+	}
+}
+
+func (p *InMemoryRateProvider) Quote(ctx context.Context, baseCurrency, quoteCurrency string) (*Rate, error) {
+	now := time.Now
+	if p.now != nil {
+		now = p.now
+	}
+
+	if baseCurrency == quoteCurrency {
+		return &Rate{
+			ProviderID:    p.ProviderID,
+			BaseCurrency:  baseCurrency,
+			QuoteCurrency: quoteCurrency,
+			Rate:          1,
+			QuotedAt:      now(),
+			ExpiresAt:     now().Add(p.TTL),
+		}, nil
+	}
+
+	key := baseCurrency + "/" + quoteCurrency
+	rate, ok := p.Rates[key]
+	if !ok {
+		return nil, fmt.Errorf("fx: no rate available for %s", key)
+	}
+
+	return &Rate{
+		ProviderID:    p.ProviderID,
+		BaseCurrency:  baseCurrency,
+		QuoteCurrency: quoteCurrency,
+		Rate:          rate,
+		QuotedAt:      now(),
+		ExpiresAt:     now().Add(p.TTL),
+	}, nil
+}
+
+// HTTPRateProvider quotes rates from an external rates API over HTTP.
+type HTTPRateProvider struct {
+	ProviderID string
+	BaseURL    string
+	Client     *http.Client
+}
+
+func NewHTTPRateProvider(providerID, baseURL string, client *http.Client) *HTTPRateProvider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPRateProvider{
+		ProviderID: providerID,
+		BaseURL:    baseURL,
+		Client:     client,
+	}
+}
+
+func (p *HTTPRateProvider) Quote(ctx context.Context, baseCurrency, quoteCurrency string) (*Rate, error) {
+	url := fmt.Sprintf("%s/rates?base=%s&quote=%s", p.BaseURL, baseCurrency, quoteCurrency)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fx: building quote request: %w", err)
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fx: quote request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fx: quote request returned status %d", resp.StatusCode)
+	}
+
+	var payload struct {
+		Rate      float64   `json:"rate"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("fx: decoding quote response: %w", err)
+	}
+
+	return &Rate{
+		ProviderID:    p.ProviderID,
+		BaseCurrency:  baseCurrency,
+		QuoteCurrency: quoteCurrency,
+		Rate:          payload.Rate,
+		QuotedAt:      time.Now(),
+		ExpiresAt:     payload.ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,74 @@
+package activities
+
+import (
+	"context"
+
+	"ulascansenturk/service/internal/webhooks"
+
+	"github.com/google/uuid"
+)
+
+type DeliverWebhookParams struct {
+	UserID        uuid.UUID
+	TransactionID uuid.UUID
+	ReferenceID   uuid.UUID
+	Direction     webhooks.Direction
+	Amount        int
+	CurrencyCode  string
+	Status        string
+}
+
+// DeliverWebhook fans a transaction lifecycle event out to every active
+// endpoint registered for the user. Each delivery is signed with that
+// endpoint's own secret and its attempt is persisted regardless of outcome,
+// so a failed delivery can be replayed later. Retries between attempts are
+// the calling workflow's Temporal exponential-backoff retry policy, not a
+// loop in here; a retried call skips any endpoint that already has a
+// recorded successful attempt for this (ReferenceID, Status) pair, so one
+// sibling endpoint being down doesn't cause a healthy endpoint to be
+// re-notified on every retry. Status is part of the dedup key, not just
+// ReferenceID, because the same ReferenceID is reused across a transaction's
+// PENDING/SUCCESS/FAILED events — keying on ReferenceID alone would make a
+// delivered PENDING event permanently mask the terminal one.
+func (t *TransactionOperations) DeliverWebhook(ctx context.Context, params DeliverWebhookParams) error {
+	endpoints, findErr := t.webhookService.FindActiveEndpointsByUser(ctx, params.UserID)
+	if findErr != nil {
+		return findErr
+	}
+
+	payload := webhooks.EventPayload{
+		TransactionID: params.TransactionID,
+		ReferenceID:   params.ReferenceID,
+		Direction:     params.Direction,
+		Amount:        params.Amount,
+		CurrencyCode:  params.CurrencyCode,
+		Status:        params.Status,
+		Timestamp:     t.timeProvider.Now(),
+	}
+
+	var deliveryErr, recordErr error
+	for _, endpoint := range endpoints {
+		delivered, succeededErr := t.webhookService.HasSucceeded(ctx, endpoint.ID, params.ReferenceID, params.Status)
+		if succeededErr != nil {
+			return succeededErr
+		}
+		if delivered {
+			continue
+		}
+
+		attempt, err := t.webhookDeliverer.Deliver(ctx, endpoint, payload)
+		if attempt != nil {
+			if err := t.webhookService.RecordAttempt(ctx, *attempt); err != nil {
+				recordErr = err
+			}
+		}
+		if err != nil {
+			deliveryErr = err
+		}
+	}
+
+	if deliveryErr != nil {
+		return deliveryErr
+	}
+	return recordErr
+}
@@ -0,0 +1,86 @@
+package activities
+
+import (
+	"context"
+
+	"ulascansenturk/service/internal/accounts"
+	"ulascansenturk/service/internal/connectors"
+
+	"github.com/google/uuid"
+	"go.temporal.io/sdk/temporal"
+)
+
+// GetAccount exposes account lookup as an activity so a workflow can decide
+// whether a transfer's destination routes to an external connector before
+// it runs ReserveFunds.
+func (t *TransactionOperations) GetAccount(ctx context.Context, accountID uuid.UUID) (*accounts.Account, error) {
+	account, err := t.accountsService.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+type InitiateExternalTransferParams struct {
+	ConnectorID          string
+	ReferenceID          uuid.UUID
+	Amount               int
+	CurrencyCode         string
+	DestinationAccountID uuid.UUID
+	Metadata             map[string]interface{}
+}
+
+// InitiateExternalTransfer dispatches the outbound leg of a transfer to the
+// connector registered for the destination account, instead of crediting it
+// directly.
+func (t *TransactionOperations) InitiateExternalTransfer(ctx context.Context, params InitiateExternalTransferParams) (*connectors.InitiateTransferResponse, error) {
+	connector, resolveErr := t.connectorRegistry.Resolve(params.ConnectorID)
+	if resolveErr != nil {
+		return nil, temporal.NewNonRetryableApplicationError(resolveErr.Error(), "unknown-connector-err", nil)
+	}
+
+	resp, initiateErr := connector.InitiateTransfer(ctx, connectors.InitiateTransferRequest{
+		ReferenceID:   params.ReferenceID.String(),
+		Amount:        params.Amount,
+		CurrencyCode:  params.CurrencyCode,
+		DestinationID: params.DestinationAccountID.String(),
+		Metadata:      params.Metadata,
+	})
+	if initiateErr != nil {
+		return nil, initiateErr
+	}
+
+	return resp, nil
+}
+
+type PollExternalTransferStatusParams struct {
+	ConnectorID       string
+	ProviderReference string
+}
+
+// PollExternalTransferStatus is called repeatedly by the polling child
+// workflow until the provider reports a terminal status.
+func (t *TransactionOperations) PollExternalTransferStatus(ctx context.Context, params PollExternalTransferStatusParams) (*connectors.StatusResponse, error) {
+	connector, resolveErr := t.connectorRegistry.Resolve(params.ConnectorID)
+	if resolveErr != nil {
+		return nil, temporal.NewNonRetryableApplicationError(resolveErr.Error(), "unknown-connector-err", nil)
+	}
+
+	return connector.PollStatus(ctx, params.ProviderReference)
+}
+
+type CancelExternalTransferParams struct {
+	ConnectorID       string
+	ProviderReference string
+}
+
+// CancelExternalTransfer asks the provider to cancel a transfer that hasn't
+// reached a terminal state, before the internal hold is released.
+func (t *TransactionOperations) CancelExternalTransfer(ctx context.Context, params CancelExternalTransferParams) error {
+	connector, resolveErr := t.connectorRegistry.Resolve(params.ConnectorID)
+	if resolveErr != nil {
+		return temporal.NewNonRetryableApplicationError(resolveErr.Error(), "unknown-connector-err", nil)
+	}
+
+	return connector.Cancel(ctx, params.ProviderReference)
+}
@@ -0,0 +1,129 @@
+package activities
+
+import (
+	"context"
+	"time"
+
+	"ulascansenturk/service/internal/accounts"
+	"ulascansenturk/service/internal/constants"
+	"ulascansenturk/service/internal/transactions"
+
+	"github.com/google/uuid"
+)
+
+// fakeAccountsService is a minimal in-memory accounts.Service for exercising
+// TransactionOperations without a real database.
+type fakeAccountsService struct {
+	accounts map[uuid.UUID]*accounts.Account
+}
+
+func newFakeAccountsService(accts ...accounts.Account) *fakeAccountsService {
+	byID := make(map[uuid.UUID]*accounts.Account, len(accts))
+	for i := range accts {
+		acc := accts[i]
+		byID[acc.ID] = &acc
+	}
+	return &fakeAccountsService{accounts: byID}
+}
+
+func (f *fakeAccountsService) GetAccountByID(ctx context.Context, accountID uuid.UUID) (*accounts.Account, error) {
+	return f.accounts[accountID], nil
+}
+
+func (f *fakeAccountsService) UpdateBalance(ctx context.Context, accountID uuid.UUID, amount int, operation string) error {
+	acc := f.accounts[accountID]
+	if operation == constants.BalanceOperationDECREASE.String() {
+		acc.Balance -= amount
+	} else {
+		acc.Balance += amount
+	}
+	return nil
+}
+
+func (f *fakeAccountsService) ReserveBalance(ctx context.Context, accountID uuid.UUID, amount int) error {
+	return nil
+}
+
+func (f *fakeAccountsService) CaptureReservedBalance(ctx context.Context, accountID uuid.UUID, amount int) error {
+	return nil
+}
+
+func (f *fakeAccountsService) ReleaseReservedBalance(ctx context.Context, accountID uuid.UUID, amount int) error {
+	return nil
+}
+
+// fakeTransactionService is a minimal in-memory transactions.Service.
+// With failFinalizeOnce set, the first UpdateEntriesStatus call that moves
+// entries to SUCCESS fails with updateEntriesStatusErr, so rollback paths can
+// be exercised.
+type fakeTransactionService struct {
+	entries                map[uuid.UUID]transactions.TransactionEntry
+	updateEntriesStatusErr error
+	failFinalizeOnce       bool
+}
+
+func newFakeTransactionService() *fakeTransactionService {
+	return &fakeTransactionService{entries: make(map[uuid.UUID]transactions.TransactionEntry)}
+}
+
+func (f *fakeTransactionService) PostLedgerEntries(ctx context.Context, entries []transactions.TransactionEntry) ([]transactions.TransactionEntry, error) {
+	posted := make([]transactions.TransactionEntry, len(entries))
+	for i, entry := range entries {
+		entry.ID = uuid.New()
+		f.entries[entry.ID] = entry
+		posted[i] = entry
+	}
+	return posted, nil
+}
+
+func (f *fakeTransactionService) FindEntriesByReferenceIDs(ctx context.Context, referenceIDs []uuid.UUID) ([]transactions.TransactionEntry, error) {
+	var found []transactions.TransactionEntry
+	for _, entry := range f.entries {
+		for _, referenceID := range referenceIDs {
+			if entry.ReferenceID == referenceID {
+				found = append(found, entry)
+			}
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeTransactionService) UpdateEntriesStatus(ctx context.Context, entryIDs []uuid.UUID, status constants.TransactionStatus) ([]transactions.TransactionEntry, error) {
+	if f.failFinalizeOnce && status == constants.TransactionStatusSUCCESS {
+		f.failFinalizeOnce = false
+		return nil, f.updateEntriesStatusErr
+	}
+
+	updated := make([]transactions.TransactionEntry, 0, len(entryIDs))
+	for _, entryID := range entryIDs {
+		entry := f.entries[entryID]
+		entry.Status = status
+		f.entries[entryID] = entry
+		updated = append(updated, entry)
+	}
+	return updated, nil
+}
+
+func (f *fakeTransactionService) FindEntriesByMultiTransactionID(ctx context.Context, multiTransactionID uuid.UUID) ([]transactions.TransactionEntry, error) {
+	var found []transactions.TransactionEntry
+	for _, entry := range f.entries {
+		if entry.MultiTransactionID != nil && *entry.MultiTransactionID == multiTransactionID {
+			found = append(found, entry)
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeTransactionService) ListExpiredReservations(ctx context.Context, before time.Time) ([]transactions.ExpiredReservationGroup, error) {
+	return nil, nil
+}
+
+// fixedTimeProvider is a helpers.TimeProvider that always returns the same
+// instant, so expiry comparisons in tests are deterministic.
+type fixedTimeProvider struct {
+	now time.Time
+}
+
+func (f fixedTimeProvider) Now() time.Time {
+	return f.now
+}
@@ -0,0 +1,57 @@
+package activities
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+)
+
+// FXConversionParams requests a locked quote for converting SourceAmount from
+// SourceCurrency into DestinationCurrency.
+type FXConversionParams struct {
+	SourceCurrency      string
+	DestinationCurrency string
+	SourceAmount        int
+}
+
+// FXConversionResult is the quote locked for the lifetime of the transfer
+// workflow. DestinationAmount is the rounded minor-unit amount to be posted
+// on the incoming leg. ExpiresAt is the provider's quote expiry: callers that
+// hold this result across more than one activity (ReserveFunds followed by a
+// later CaptureTransfer) must check it before acting on DestinationAmount,
+// since the underlying rate is no longer guaranteed once it passes.
+type FXConversionResult struct {
+	ProviderID          string
+	Rate                float64
+	SourceAmount        int
+	DestinationAmount   int
+	SourceCurrency      string
+	DestinationCurrency string
+	ExpiresAt           time.Time
+}
+
+// FXConversion quotes and locks an exchange rate for a cross-currency
+// transfer. The returned rate is recorded on both legs of the transfer so it
+// can be reconstructed later even if the underlying provider's rate moves.
+func (t *TransactionOperations) FXConversion(ctx context.Context, params FXConversionParams) (*FXConversionResult, error) {
+	quote, quoteErr := t.rateProvider.Quote(ctx, params.SourceCurrency, params.DestinationCurrency)
+	if quoteErr != nil {
+		return nil, temporal.NewNonRetryableApplicationError(quoteErr.Error(), "error while quoting fx rate", nil)
+	}
+
+	return &FXConversionResult{
+		ProviderID:          quote.ProviderID,
+		Rate:                quote.Rate,
+		SourceAmount:        params.SourceAmount,
+		DestinationAmount:   roundAmount(float64(params.SourceAmount) * quote.Rate),
+		SourceCurrency:      params.SourceCurrency,
+		DestinationCurrency: params.DestinationCurrency,
+		ExpiresAt:           quote.ExpiresAt,
+	}, nil
+}
+
+func roundAmount(amount float64) int {
+	return int(math.Round(amount))
+}
@@ -0,0 +1,49 @@
+package activities
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.temporal.io/sdk/temporal"
+)
+
+// ExpiredReservation is a held transfer whose ReservationTTL has passed
+// without a CaptureTransfer or CancelReservation, and so is eligible for the
+// sweeper to cancel.
+type ExpiredReservation struct {
+	SourceAccountID   uuid.UUID
+	Amount            int
+	FeeAmount         *int
+	EntryReferenceIDs []uuid.UUID
+}
+
+type ListExpiredReservationsParams struct {
+	Before time.Time
+}
+
+type ListExpiredReservationsResult struct {
+	Reservations []ExpiredReservation
+}
+
+// ListExpiredReservations returns every reservation whose held entries are
+// still PENDING past their ExpiresAt, grouped by the reference ids that
+// belong to the same ReserveFunds call.
+func (t *TransactionOperations) ListExpiredReservations(ctx context.Context, params ListExpiredReservationsParams) (*ListExpiredReservationsResult, error) {
+	expired, err := t.transactionService.ListExpiredReservations(ctx, params.Before)
+	if err != nil {
+		return nil, temporal.NewNonRetryableApplicationError(err.Error(), "error while listing expired reservations", nil)
+	}
+
+	reservations := make([]ExpiredReservation, len(expired))
+	for i, group := range expired {
+		reservations[i] = ExpiredReservation{
+			SourceAccountID:   group.SourceAccountID,
+			Amount:            group.Amount,
+			FeeAmount:         group.FeeAmount,
+			EntryReferenceIDs: group.EntryReferenceIDs,
+		}
+	}
+
+	return &ListExpiredReservationsResult{Reservations: reservations}, nil
+}
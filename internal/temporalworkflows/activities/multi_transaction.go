@@ -0,0 +1,183 @@
+package activities
+
+import (
+	"context"
+	"gorm.io/datatypes"
+
+	"ulascansenturk/service/internal/constants"
+	"ulascansenturk/service/internal/transactions"
+
+	"github.com/google/uuid"
+)
+
+// TransferLeg is one account-to-account movement within a MultiTransaction.
+// A Send groups exactly one leg (plus an optional fee), a Swap groups an
+// outbound leg in the source currency with an inbound leg in the
+// destination currency, and a Bridge groups a chain of intermediate legs
+// hopping through one or more clearing accounts.
+type TransferLeg struct {
+	SourceAccountID      uuid.UUID
+	DestinationAccountID uuid.UUID
+	Amount               int
+	ReferenceID          uuid.UUID
+	EntryType            constants.EntryType
+	CurrencyCode         constants.CurrencyCode
+	Metadata             map[string]interface{}
+}
+
+// MultiTransactionParams groups every leg of a Send, Swap, or Bridge
+// operation under one MultiTransactionID so they post and finalize as a
+// single unit.
+type MultiTransactionParams struct {
+	MultiTransactionID uuid.UUID
+	Type               constants.MultiTransactionType
+	Legs               []TransferLeg
+}
+
+type MultiTransactionResult struct {
+	MultiTransactionID uuid.UUID
+	Type               constants.MultiTransactionType
+	Entries            []transactions.TransactionEntry
+}
+
+// ExecuteMultiTransaction posts every leg of a grouped operation, moves the
+// corresponding account balances, and finalizes them together: if any leg
+// fails to transition to SUCCESS, the whole group is rolled back to
+// CANCELLED and every balance movement is reversed, rather than left half
+// committed.
+func (t *TransactionOperations) ExecuteMultiTransaction(ctx context.Context, params MultiTransactionParams) (*MultiTransactionResult, error) {
+	entries := make([]transactions.TransactionEntry, len(params.Legs))
+	for i, leg := range params.Legs {
+		entries[i] = transactions.TransactionEntry{
+			DebitAccountID:     leg.SourceAccountID,
+			CreditAccountID:    leg.DestinationAccountID,
+			Amount:             leg.Amount,
+			CurrencyCode:       leg.CurrencyCode,
+			ReferenceID:        leg.ReferenceID,
+			EntryType:          leg.EntryType,
+			Status:             constants.TransactionStatusPENDING,
+			MultiTransactionID: &params.MultiTransactionID,
+			Metadata:           datatypes.JSONMap(leg.Metadata),
+		}
+	}
+
+	posted, postErr := t.PostLedgerEntries(ctx, PostLedgerEntriesParams{Entries: entries})
+	if postErr != nil {
+		return nil, postErr
+	}
+
+	if balanceErr := t.moveMultiTransactionBalances(ctx, params.Legs); balanceErr != nil {
+		return nil, balanceErr
+	}
+
+	finalizedEntries, finalizeErr := t.finalizeMultiTransaction(ctx, posted.Entries)
+	if finalizeErr != nil {
+		if reverseErr := t.reverseMultiTransactionBalances(ctx, params.Legs); reverseErr != nil {
+			return nil, reverseErr
+		}
+		return nil, finalizeErr
+	}
+
+	return &MultiTransactionResult{
+		MultiTransactionID: params.MultiTransactionID,
+		Type:               params.Type,
+		Entries:            finalizedEntries,
+	}, nil
+}
+
+// moveMultiTransactionBalances applies every leg's balance movement, mirroring
+// what Transfer's updateAccountBalances does for a plain Send: a Swap or
+// Bridge posts ledger entries the same way a Send does, so it must move real
+// balance the same way too, instead of leaving SUCCESS-status entries with no
+// corresponding account movement.
+func (t *TransactionOperations) moveMultiTransactionBalances(ctx context.Context, legs []TransferLeg) error {
+	for _, leg := range legs {
+		if err := t.accountsService.UpdateBalance(ctx, leg.SourceAccountID, leg.Amount, constants.BalanceOperationDECREASE.String()); err != nil {
+			return err
+		}
+		if err := t.accountsService.UpdateBalance(ctx, leg.DestinationAccountID, leg.Amount, constants.BalanceOperationINCREASE.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reverseMultiTransactionBalances undoes moveMultiTransactionBalances when
+// finalizeMultiTransaction fails partway, so a rolled-back Swap or Bridge
+// doesn't leave money moved with no successful entries to show for it.
+func (t *TransactionOperations) reverseMultiTransactionBalances(ctx context.Context, legs []TransferLeg) error {
+	for _, leg := range legs {
+		if err := t.accountsService.UpdateBalance(ctx, leg.DestinationAccountID, leg.Amount, constants.BalanceOperationDECREASE.String()); err != nil {
+			return err
+		}
+		if err := t.accountsService.UpdateBalance(ctx, leg.SourceAccountID, leg.Amount, constants.BalanceOperationINCREASE.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finalizeMultiTransaction transitions every entry in the group to SUCCESS
+// as one call. If that fails partway, every entry in the group is rolled
+// back to CANCELLED so a Swap's FX leg can never commit without its paired
+// leg, nor can one hop of a Bridge commit without the rest of the chain.
+func (t *TransactionOperations) finalizeMultiTransaction(ctx context.Context, entries []transactions.TransactionEntry) ([]transactions.TransactionEntry, error) {
+	entryIDs := make([]uuid.UUID, len(entries))
+	for i, entry := range entries {
+		entryIDs[i] = entry.ID
+	}
+
+	updatedEntries, updateErr := t.transactionService.UpdateEntriesStatus(ctx, entryIDs, constants.TransactionStatusSUCCESS)
+	if updateErr != nil {
+		if _, rollbackErr := t.transactionService.UpdateEntriesStatus(ctx, entryIDs, constants.TransactionStatusCANCELLED); rollbackErr != nil {
+			return nil, rollbackErr
+		}
+		return nil, updateErr
+	}
+
+	return updatedEntries, nil
+}
+
+// GetMultiTransaction returns every child entry posted under a grouped
+// operation's MultiTransactionID, e.g. an FX swap's outbound leg, inbound
+// leg and fee.
+func (t *TransactionOperations) GetMultiTransaction(ctx context.Context, multiTransactionID uuid.UUID) (*MultiTransactionResult, error) {
+	entries, err := t.transactionService.FindEntriesByMultiTransactionID(ctx, multiTransactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MultiTransactionResult{
+		MultiTransactionID: multiTransactionID,
+		Entries:            entries,
+	}, nil
+}
+
+// NewSwapParams builds a Swap MultiTransaction: an FX-quoted outbound leg in
+// the source currency paired with an inbound leg in the destination
+// currency, plus an optional fee leg, e.g. converting between two of the
+// same user's accounts.
+func NewSwapParams(multiTransactionID uuid.UUID, outbound, inbound TransferLeg, fee *TransferLeg) MultiTransactionParams {
+	legs := []TransferLeg{outbound, inbound}
+	if fee != nil {
+		legs = append(legs, *fee)
+	}
+
+	return MultiTransactionParams{
+		MultiTransactionID: multiTransactionID,
+		Type:               constants.MultiTransactionTypeSWAP,
+		Legs:               legs,
+	}
+}
+
+// NewBridgeParams builds a Bridge MultiTransaction: an ordered chain of
+// intermediate legs hopping through one or more clearing accounts to
+// connect two rails or currencies that don't settle directly with each
+// other.
+func NewBridgeParams(multiTransactionID uuid.UUID, legs []TransferLeg) MultiTransactionParams {
+	return MultiTransactionParams{
+		MultiTransactionID: multiTransactionID,
+		Type:               constants.MultiTransactionTypeBRIDGE,
+		Legs:               legs,
+	}
+}
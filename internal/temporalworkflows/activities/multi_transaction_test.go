@@ -0,0 +1,46 @@
+package activities
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"ulascansenturk/service/internal/accounts"
+	"ulascansenturk/service/internal/constants"
+
+	"github.com/google/uuid"
+)
+
+func TestExecuteMultiTransaction_RollsBackBalancesOnPartialFailure(t *testing.T) {
+	sourceID, destinationID := uuid.New(), uuid.New()
+	accountsService := newFakeAccountsService(
+		accounts.Account{ID: sourceID, Balance: 1000, Currency: "USD", Status: constants.AccountStatusACTIVE},
+		accounts.Account{ID: destinationID, Balance: 0, Currency: "EUR", Status: constants.AccountStatusACTIVE},
+	)
+	transactionService := newFakeTransactionService()
+	transactionService.failFinalizeOnce = true
+	transactionService.updateEntriesStatusErr = errors.New("finalize failed")
+
+	ops := &TransactionOperations{
+		transactionService: transactionService,
+		accountsService:    accountsService,
+	}
+
+	multiTransactionID := uuid.New()
+	params := NewSwapParams(multiTransactionID,
+		TransferLeg{SourceAccountID: sourceID, DestinationAccountID: destinationID, Amount: 100, CurrencyCode: constants.CurrencyCode("USD"), ReferenceID: uuid.New(), EntryType: constants.EntryTypeFXDebit},
+		TransferLeg{SourceAccountID: sourceID, DestinationAccountID: destinationID, Amount: 90, CurrencyCode: constants.CurrencyCode("EUR"), ReferenceID: uuid.New(), EntryType: constants.EntryTypeFXCredit},
+		nil,
+	)
+
+	if _, err := ops.ExecuteMultiTransaction(context.Background(), params); err == nil {
+		t.Fatal("expected ExecuteMultiTransaction to surface the finalize error")
+	}
+
+	if balance := accountsService.accounts[sourceID].Balance; balance != 1000 {
+		t.Errorf("expected source balance to be rolled back to 1000, got %d", balance)
+	}
+	if balance := accountsService.accounts[destinationID].Balance; balance != 0 {
+		t.Errorf("expected destination balance to be rolled back to 0, got %d", balance)
+	}
+}
@@ -0,0 +1,83 @@
+package activities
+
+import (
+	"context"
+	"fmt"
+
+	"ulascansenturk/service/internal/transactions"
+
+	"github.com/google/uuid"
+	"go.temporal.io/sdk/temporal"
+)
+
+// PostLedgerEntriesParams is a balanced set of debit/credit ledger rows to be
+// written atomically. Every entry must carry both sides of its movement
+// (DebitAccountID and CreditAccountID) so the set can be validated and
+// posted in a single DB transaction instead of one create call per leg.
+type PostLedgerEntriesParams struct {
+	Entries []transactions.TransactionEntry
+}
+
+type PostLedgerEntriesResult struct {
+	Entries []transactions.TransactionEntry
+}
+
+// PostLedgerEntries writes a balanced set of TransactionEntry rows in one DB
+// transaction. The transactions service enforces sum(debits) == sum(credits)
+// at commit and relies on the (user_id, reference_id, debit_account_id,
+// credit_account_id, entry_type) uniqueness constraint to make re-running
+// this activity after a Temporal retry a no-op rather than a duplicate post.
+func (t *TransactionOperations) PostLedgerEntries(ctx context.Context, params PostLedgerEntriesParams) (*PostLedgerEntriesResult, error) {
+	if err := validateBalancedEntries(params.Entries); err != nil {
+		return nil, temporal.NewNonRetryableApplicationError(err.Error(), "unbalanced-ledger-entries-err", err)
+	}
+
+	postedEntries, postErr := t.transactionService.PostLedgerEntries(ctx, params.Entries)
+	if postErr != nil {
+		return nil, temporal.NewNonRetryableApplicationError(postErr.Error(), "error while posting ledger entries", nil)
+	}
+
+	return &PostLedgerEntriesResult{Entries: postedEntries}, nil
+}
+
+// validateBalancedEntries nets every entry's Amount into its debit account
+// and its credit account, then asserts the two account-keyed totals agree.
+// An entry whose DebitAccountID and CreditAccountID are the same account is
+// rejected outright: it cancels itself out and never moves money anywhere,
+// so it isn't a real double-entry movement at all (a fee debited from and
+// credited back to the same account being the common way this creeps in).
+func validateBalancedEntries(entries []transactions.TransactionEntry) error {
+	debitTotals := make(map[uuid.UUID]int, len(entries))
+	creditTotals := make(map[uuid.UUID]int, len(entries))
+
+	for _, entry := range entries {
+		if entry.DebitAccountID == entry.CreditAccountID {
+			return fmt.Errorf("ledger entry %s has no counterparty: debit and credit account are both %s", entry.EntryType, entry.DebitAccountID)
+		}
+		debitTotals[entry.DebitAccountID] += entry.Amount
+		creditTotals[entry.CreditAccountID] += entry.Amount
+	}
+
+	var totalDebits, totalCredits int
+	for _, amount := range debitTotals {
+		totalDebits += amount
+	}
+	for _, amount := range creditTotals {
+		totalCredits += amount
+	}
+
+	if totalDebits != totalCredits {
+		return fmt.Errorf("ledger entries are not balanced: debits %d, credits %d", totalDebits, totalCredits)
+	}
+
+	return nil
+}
+
+func entryByType(entries []transactions.TransactionEntry, entryType string) *transactions.TransactionEntry {
+	for i := range entries {
+		if string(entries[i].EntryType) == entryType {
+			return &entries[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package activities
+
+import (
+	"testing"
+
+	"ulascansenturk/service/internal/constants"
+	"ulascansenturk/service/internal/transactions"
+
+	"github.com/google/uuid"
+)
+
+func TestValidateBalancedEntries_RejectsUnbalancedSet(t *testing.T) {
+	source := uuid.New()
+	destination := uuid.New()
+
+	entries := []transactions.TransactionEntry{
+		{DebitAccountID: source, CreditAccountID: destination, Amount: 100, EntryType: constants.EntryTypeOutgoing},
+		{DebitAccountID: source, CreditAccountID: destination, Amount: 90, EntryType: constants.EntryTypeIncoming},
+	}
+
+	if err := validateBalancedEntries(entries); err == nil {
+		t.Fatal("expected an error for an unbalanced entry set, got nil")
+	}
+}
+
+func TestValidateBalancedEntries_AcceptsBalancedSet(t *testing.T) {
+	source := uuid.New()
+	destination := uuid.New()
+	feeAccount := uuid.New()
+
+	entries := []transactions.TransactionEntry{
+		{DebitAccountID: source, CreditAccountID: destination, Amount: 100, EntryType: constants.EntryTypeOutgoing},
+		{DebitAccountID: source, CreditAccountID: destination, Amount: 100, EntryType: constants.EntryTypeIncoming},
+		{DebitAccountID: source, CreditAccountID: feeAccount, Amount: 5, EntryType: constants.EntryTypeFee},
+	}
+
+	if err := validateBalancedEntries(entries); err != nil {
+		t.Fatalf("expected a balanced entry set to validate, got %v", err)
+	}
+}
+
+func TestValidateBalancedEntries_RejectsSelfReferencingEntry(t *testing.T) {
+	account := uuid.New()
+
+	entries := []transactions.TransactionEntry{
+		{DebitAccountID: account, CreditAccountID: account, Amount: 5, EntryType: constants.EntryTypeFee},
+	}
+
+	if err := validateBalancedEntries(entries); err == nil {
+		t.Fatal("expected an error for an entry with no real counterparty, got nil")
+	}
+}
@@ -0,0 +1,197 @@
+package activities
+
+import (
+	"context"
+	"time"
+
+	"ulascansenturk/service/internal/constants"
+	"ulascansenturk/service/internal/transactions"
+
+	"github.com/google/uuid"
+	"go.temporal.io/sdk/temporal"
+)
+
+// ReserveFundsParams authorizes a transfer: it holds the source amount (plus
+// fee) against the source account's available balance without touching
+// posted balance, mirroring a card authorization/capture flow. The held
+// entries expire at ReservationTTL so a stuck workflow can't lock funds
+// forever; see ReservationSweeperWorkflow.
+type ReserveFundsParams struct {
+	TransferParams
+	ReservationTTL time.Duration
+}
+
+type ReservationResult struct {
+	Entries []transactions.TransactionEntry
+	// DestinationAmount is the amount to credit to the destination account
+	// on capture: params.Amount for a same-currency reservation, or the
+	// FX-converted amount when ReserveFunds ran a conversion. CaptureTransfer
+	// must use this rather than the source-side Amount.
+	DestinationAmount int
+	ExpiresAt         time.Time
+	// FXQuoteExpiresAt is set when this reservation locked a cross-currency
+	// rate, and is nil otherwise. CaptureTransfer rejects the capture once
+	// this has passed, since the locked rate behind DestinationAmount is no
+	// longer guaranteed by the provider.
+	FXQuoteExpiresAt *time.Time
+}
+
+func (t *TransactionOperations) ReserveFunds(ctx context.Context, params ReserveFundsParams) (*ReservationResult, error) {
+	validAccounts, accountsErr := t.validateAccount(ctx, params.Amount, params.FeeAmount, params.SourceAccountID, params.DestinationAccountID)
+	if accountsErr != nil {
+		return nil, temporal.NewNonRetryableApplicationError("Error on validating accounts", "validate-accounts-err", accountsErr)
+	}
+
+	if feeAccountErr := validateFeeAccount(params.TransferParams); feeAccountErr != nil {
+		return nil, temporal.NewNonRetryableApplicationError(feeAccountErr.Error(), "missing-fee-account-err", feeAccountErr)
+	}
+
+	var fxResult *FXConversionResult
+	if validAccounts.SourceAccount.Currency != validAccounts.DestinationAccount.Currency {
+		converted, fxErr := t.FXConversion(ctx, FXConversionParams{
+			SourceCurrency:      validAccounts.SourceAccount.Currency,
+			DestinationCurrency: validAccounts.DestinationAccount.Currency,
+			SourceAmount:        params.Amount,
+		})
+		if fxErr != nil {
+			return nil, fxErr
+		}
+		fxResult = converted
+	}
+
+	expiresAt := t.timeProvider.Now().Add(params.ReservationTTL)
+	entries := t.buildTransferEntries(params.TransferParams, *validAccounts.SourceAccount, *validAccounts.DestinationAccount, fxResult)
+	for i := range entries {
+		entries[i].ExpiresAt = &expiresAt
+	}
+
+	posted, postErr := t.PostLedgerEntries(ctx, PostLedgerEntriesParams{Entries: entries})
+	if postErr != nil {
+		return nil, postErr
+	}
+
+	if reserveErr := t.accountsService.ReserveBalance(ctx, validAccounts.SourceAccount.ID, totalHoldAmount(params.Amount, params.FeeAmount)); reserveErr != nil {
+		return nil, reserveErr
+	}
+
+	destinationAmount := params.Amount
+	var fxQuoteExpiresAt *time.Time
+	if fxResult != nil {
+		destinationAmount = fxResult.DestinationAmount
+		fxQuoteExpiresAt = &fxResult.ExpiresAt
+	}
+
+	return &ReservationResult{
+		Entries:           posted.Entries,
+		DestinationAmount: destinationAmount,
+		ExpiresAt:         expiresAt,
+		FXQuoteExpiresAt:  fxQuoteExpiresAt,
+	}, nil
+}
+
+// CaptureTransferParams identifies a prior ReserveFunds hold to move from
+// reserved into posted balance. Amount is the source-side hold amount
+// released via CaptureReservedBalance; DestinationAmount is what's actually
+// credited to the destination and, for a cross-currency reservation, is the
+// FX-converted amount rather than Amount. FXQuoteExpiresAt carries the
+// locked quote's expiry so a capture long after ReserveFunds can't credit a
+// rate the provider no longer honors.
+type CaptureTransferParams struct {
+	SourceAccountID      uuid.UUID
+	DestinationAccountID uuid.UUID
+	Amount               int
+	DestinationAmount    int
+	FeeAmount            *int
+	EntryReferenceIDs    []uuid.UUID
+	FXQuoteExpiresAt     *time.Time
+}
+
+type CaptureTransferResult struct {
+	Entries []transactions.TransactionEntry
+}
+
+// CaptureTransfer moves a reservation's held amount into the destination's
+// posted balance and marks its ledger entries SUCCESS.
+func (t *TransactionOperations) CaptureTransfer(ctx context.Context, params CaptureTransferParams) (*CaptureTransferResult, error) {
+	if params.FXQuoteExpiresAt != nil && !t.timeProvider.Now().Before(*params.FXQuoteExpiresAt) {
+		return nil, temporal.NewNonRetryableApplicationError("fx quote expired before capture; cancel the reservation and retry", "fx-quote-expired-err", nil)
+	}
+
+	updatedEntries, updateErr := t.updateEntriesByReferenceIDs(ctx, params.EntryReferenceIDs, constants.TransactionStatusSUCCESS)
+	if updateErr != nil {
+		return nil, updateErr
+	}
+
+	if captureErr := t.accountsService.CaptureReservedBalance(ctx, params.SourceAccountID, totalHoldAmount(params.Amount, params.FeeAmount)); captureErr != nil {
+		return nil, captureErr
+	}
+
+	if balanceErr := t.accountsService.UpdateBalance(ctx, params.DestinationAccountID, params.DestinationAmount, constants.BalanceOperationINCREASE.String()); balanceErr != nil {
+		return nil, balanceErr
+	}
+
+	return &CaptureTransferResult{Entries: updatedEntries}, nil
+}
+
+// CancelReservationParams identifies a prior ReserveFunds hold to release.
+type CancelReservationParams struct {
+	SourceAccountID   uuid.UUID
+	Amount            int
+	FeeAmount         *int
+	EntryReferenceIDs []uuid.UUID
+}
+
+// CancelReservation releases a ReserveFunds hold back to available balance
+// and marks its ledger entries CANCELLED. Invoked either by the workflow
+// itself when the external leg fails to start, fails, or doesn't reach a
+// terminal status in time, or by ReservationSweeperWorkflow once the hold
+// has expired. A failed capture is not routed here; see
+// FlagReservationForManualReview.
+func (t *TransactionOperations) CancelReservation(ctx context.Context, params CancelReservationParams) error {
+	if _, updateErr := t.updateEntriesByReferenceIDs(ctx, params.EntryReferenceIDs, constants.TransactionStatusCANCELLED); updateErr != nil {
+		return updateErr
+	}
+
+	return t.accountsService.ReleaseReservedBalance(ctx, params.SourceAccountID, totalHoldAmount(params.Amount, params.FeeAmount))
+}
+
+// FlagReservationForManualReviewParams identifies a reservation whose
+// external leg has already succeeded but whose CaptureTransfer could not be
+// completed (for example, the locked FX quote expired mid-poll).
+type FlagReservationForManualReviewParams struct {
+	EntryReferenceIDs []uuid.UUID
+}
+
+// FlagReservationForManualReview marks a reservation's held entries
+// REQUIRES_REVIEW instead of leaving them PENDING. ListExpiredReservations
+// only sweeps entries that are still PENDING past their ExpiresAt, so this
+// pulls the reservation out of the sweeper's reach: once the external leg has
+// succeeded, ReservationSweeperWorkflow auto-cancelling the hold would
+// release money back to the source account that has already left it
+// externally, rather than the capture failure getting the operator attention
+// it needs.
+func (t *TransactionOperations) FlagReservationForManualReview(ctx context.Context, params FlagReservationForManualReviewParams) error {
+	_, err := t.updateEntriesByReferenceIDs(ctx, params.EntryReferenceIDs, constants.TransactionStatusREQUIRES_REVIEW)
+	return err
+}
+
+func (t *TransactionOperations) updateEntriesByReferenceIDs(ctx context.Context, referenceIDs []uuid.UUID, status constants.TransactionStatus) ([]transactions.TransactionEntry, error) {
+	entries, findErr := t.transactionService.FindEntriesByReferenceIDs(ctx, referenceIDs)
+	if findErr != nil {
+		return nil, findErr
+	}
+
+	entryIDs := make([]uuid.UUID, len(entries))
+	for i, entry := range entries {
+		entryIDs[i] = entry.ID
+	}
+
+	return t.transactionService.UpdateEntriesStatus(ctx, entryIDs, status)
+}
+
+func totalHoldAmount(amount int, feeAmount *int) int {
+	if feeAmount == nil {
+		return amount
+	}
+	return amount + *feeAmount
+}
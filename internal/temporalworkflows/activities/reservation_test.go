@@ -0,0 +1,100 @@
+package activities
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ulascansenturk/service/internal/accounts"
+	"ulascansenturk/service/internal/constants"
+
+	"github.com/google/uuid"
+)
+
+func TestCaptureTransfer_CreditsDestinationAmountNotSourceAmount(t *testing.T) {
+	sourceID, destinationID := uuid.New(), uuid.New()
+	accountsService := newFakeAccountsService(
+		accounts.Account{ID: sourceID, Balance: 1000, Currency: "USD", Status: constants.AccountStatusACTIVE},
+		accounts.Account{ID: destinationID, Balance: 0, Currency: "EUR", Status: constants.AccountStatusACTIVE},
+	)
+	transactionService := newFakeTransactionService()
+
+	ops := &TransactionOperations{
+		transactionService: transactionService,
+		accountsService:    accountsService,
+		timeProvider:       fixedTimeProvider{now: time.Unix(0, 0)},
+	}
+
+	_, err := ops.CaptureTransfer(context.Background(), CaptureTransferParams{
+		SourceAccountID:      sourceID,
+		DestinationAccountID: destinationID,
+		Amount:               100,
+		DestinationAmount:    90, // FX-converted amount, deliberately different from Amount
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if balance := accountsService.accounts[destinationID].Balance; balance != 90 {
+		t.Errorf("expected destination to be credited the converted amount 90, got %d", balance)
+	}
+}
+
+func TestCaptureTransfer_RejectsExpiredFXQuote(t *testing.T) {
+	sourceID, destinationID := uuid.New(), uuid.New()
+	accountsService := newFakeAccountsService(
+		accounts.Account{ID: sourceID, Balance: 1000, Currency: "USD", Status: constants.AccountStatusACTIVE},
+		accounts.Account{ID: destinationID, Balance: 0, Currency: "EUR", Status: constants.AccountStatusACTIVE},
+	)
+	transactionService := newFakeTransactionService()
+
+	now := time.Unix(1000, 0)
+	expired := now.Add(-time.Minute)
+	ops := &TransactionOperations{
+		transactionService: transactionService,
+		accountsService:    accountsService,
+		timeProvider:       fixedTimeProvider{now: now},
+	}
+
+	_, err := ops.CaptureTransfer(context.Background(), CaptureTransferParams{
+		SourceAccountID:      sourceID,
+		DestinationAccountID: destinationID,
+		Amount:               100,
+		DestinationAmount:    90,
+		FXQuoteExpiresAt:     &expired,
+	})
+	if err == nil {
+		t.Fatal("expected capture to reject an expired fx quote")
+	}
+
+	if balance := accountsService.accounts[destinationID].Balance; balance != 0 {
+		t.Errorf("expected no balance movement for a rejected capture, got %d", balance)
+	}
+}
+
+func TestReserveFunds_RejectsHoldPastAvailableBalance(t *testing.T) {
+	sourceID, destinationID := uuid.New(), uuid.New()
+	accountsService := newFakeAccountsService(
+		accounts.Account{ID: sourceID, Balance: 1000, ReservedAmount: 900, Currency: "USD", Status: constants.AccountStatusACTIVE},
+		accounts.Account{ID: destinationID, Balance: 0, Currency: "USD", Status: constants.AccountStatusACTIVE},
+	)
+	transactionService := newFakeTransactionService()
+
+	ops := &TransactionOperations{
+		transactionService: transactionService,
+		accountsService:    accountsService,
+		timeProvider:       fixedTimeProvider{now: time.Unix(0, 0)},
+	}
+
+	_, err := ops.ReserveFunds(context.Background(), ReserveFundsParams{
+		TransferParams: TransferParams{
+			Amount:               200,
+			SourceAccountID:      sourceID,
+			DestinationAccountID: destinationID,
+		},
+		ReservationTTL: time.Hour,
+	})
+	if err == nil {
+		t.Fatal("expected ReserveFunds to reject a hold that exceeds available balance (posted balance minus existing reservations)")
+	}
+}
@@ -6,48 +6,60 @@ import (
 	"gorm.io/datatypes"
 	"time"
 	"ulascansenturk/service/internal/accounts"
+	"ulascansenturk/service/internal/connectors"
 	"ulascansenturk/service/internal/constants"
+	"ulascansenturk/service/internal/fx"
 	"ulascansenturk/service/internal/helpers"
 	"ulascansenturk/service/internal/transactions"
+	"ulascansenturk/service/internal/webhooks"
 
 	"github.com/google/uuid"
 	"go.temporal.io/sdk/temporal"
 )
 
 type TransactionOperations struct {
-	finderOrCreatorService transactions.FinderOrCreator
-	transactionService     transactions.Service
-	accountsService        accounts.Service
-	timeProvider           helpers.TimeProvider
+	transactionService transactions.Service
+	accountsService    accounts.Service
+	timeProvider       helpers.TimeProvider
+	rateProvider       fx.RateProvider
+	connectorRegistry  *connectors.Registry
+	webhookService     webhooks.Service
+	webhookDeliverer   *webhooks.Deliverer
 }
 
-func NewTransactionOperations(finderOrCreatorService transactions.FinderOrCreator, transactionsService transactions.Service, accountsService accounts.Service, timeProvider helpers.TimeProvider) *TransactionOperations {
+func NewTransactionOperations(transactionsService transactions.Service, accountsService accounts.Service, timeProvider helpers.TimeProvider, rateProvider fx.RateProvider, connectorRegistry *connectors.Registry, webhookService webhooks.Service, webhookDeliverer *webhooks.Deliverer) *TransactionOperations {
 	return &TransactionOperations{
-		finderOrCreatorService: finderOrCreatorService,
-		transactionService:     transactionsService,
-		accountsService:        accountsService,
-		timeProvider:           timeProvider,
+		transactionService: transactionsService,
+		accountsService:    accountsService,
+		timeProvider:       timeProvider,
+		rateProvider:       rateProvider,
+		connectorRegistry:  connectorRegistry,
+		webhookService:     webhookService,
+		webhookDeliverer:   webhookDeliverer,
 	}
 }
 
 type TransferParams struct {
 	Amount                            int
 	FeeAmount                         *int
+	FeeAccountID                      uuid.UUID
 	Metadata                          *map[string]interface{}
 	DestinationAccountID              uuid.UUID
 	SourceTransactionReferenceID      uuid.UUID
 	DestinationTransactionReferenceID uuid.UUID
 	FeeTransactionReferenceID         uuid.UUID
 	SourceAccountID                   uuid.UUID
+	MultiTransactionID                uuid.UUID
 }
 
 type TransferResult struct {
 	SourceTransactionReferenceID      uuid.UUID
 	DestinationTransactionReferenceID uuid.UUID
 	FeeTransactionReferenceID         uuid.UUID
-	FeeTransaction                    *transactions.Transaction
-	SourceTransaction                 *transactions.Transaction
-	DestinationTransaction            *transactions.Transaction
+	MultiTransactionID                uuid.UUID
+	FeeEntry                          *transactions.TransactionEntry
+	SourceEntry                       *transactions.TransactionEntry
+	DestinationEntry                  *transactions.TransactionEntry
 }
 
 func (t *TransactionOperations) Transfer(ctx context.Context, params TransferParams) (*TransferResult, error) {
@@ -57,116 +69,132 @@ func (t *TransactionOperations) Transfer(ctx context.Context, params TransferPar
 
 	}
 
-	pendingOutGoingTransaction, pendingOutGoingTransactionErr := t.createPendingOutgoingTransaction(ctx, params, *validAccounts.SourceAccount)
-	if pendingOutGoingTransactionErr != nil {
-		return nil, pendingOutGoingTransactionErr
+	if feeAccountErr := validateFeeAccount(params); feeAccountErr != nil {
+		return nil, temporal.NewNonRetryableApplicationError(feeAccountErr.Error(), "missing-fee-account-err", feeAccountErr)
 	}
 
-	pendingFeeTrx, pendingFeeTrxErr := t.createPendingFeeTransaction(ctx, params, *validAccounts.SourceAccount)
-	if pendingFeeTrxErr != nil {
-		return nil, pendingFeeTrxErr
+	var fxResult *FXConversionResult
+	if validAccounts.SourceAccount.Currency != validAccounts.DestinationAccount.Currency {
+		converted, fxErr := t.FXConversion(ctx, FXConversionParams{
+			SourceCurrency:      validAccounts.SourceAccount.Currency,
+			DestinationCurrency: validAccounts.DestinationAccount.Currency,
+			SourceAmount:        params.Amount,
+		})
+		if fxErr != nil {
+			return nil, fxErr
+		}
+		fxResult = converted
 	}
 
-	pendingIncomingTransaction, pendingIncomingTransactionErr := t.createPendingIncomingTransaction(ctx, params, *validAccounts.DestinationAccount)
-	if pendingIncomingTransactionErr != nil {
-		return nil, pendingIncomingTransactionErr
+	entries := t.buildTransferEntries(params, *validAccounts.SourceAccount, *validAccounts.DestinationAccount, fxResult)
+
+	posted, postErr := t.PostLedgerEntries(ctx, PostLedgerEntriesParams{Entries: entries})
+	if postErr != nil {
+		return nil, postErr
 	}
 
-	if updateAccountBalanceErr := t.updateAccountBalances(ctx, *validAccounts.SourceAccount, *validAccounts.DestinationAccount, params); updateAccountBalanceErr != nil {
+	if updateAccountBalanceErr := t.updateAccountBalances(ctx, *validAccounts.SourceAccount, *validAccounts.DestinationAccount, params, fxResult); updateAccountBalanceErr != nil {
 		return nil, updateAccountBalanceErr
 	}
 
-	updatedTransactions, finalizeTranscationErr := t.finalizeTransactions(ctx, pendingOutGoingTransaction, pendingIncomingTransaction, pendingFeeTrx)
-	if finalizeTranscationErr != nil {
-		return nil, finalizeTranscationErr
+	updatedEntries, finalizeErr := t.finalizeMultiTransaction(ctx, posted.Entries)
+	if finalizeErr != nil {
+		return nil, finalizeErr
 	}
 
-	return t.createTransferResult(params, &updatedTransactions.OutgoingTrx, &updatedTransactions.IncomingTrx, updatedTransactions.FeeTrx), nil
+	return t.createTransferResult(params, updatedEntries), nil
 }
 
-func (t *TransactionOperations) createPendingOutgoingTransaction(ctx context.Context, params TransferParams, sourceAccount accounts.Account) (*transactions.Transaction, error) {
-	pendingOutgoingTransactionParams := &transactions.Transaction{
-		UserID:       &sourceAccount.UserID,
-		Amount:       params.Amount,
-		AccountID:    sourceAccount.ID,
-		CurrencyCode: constants.CurrencyCode(sourceAccount.Currency),
-		ReferenceID:  params.SourceTransactionReferenceID,
-		Metadata: datatypes.JSONMap(map[string]interface{}{
-			"OperationType":        "Transfer",
-			"LinkedTransactionID":  params.SourceTransactionReferenceID.String(),
-			"LinkedAccountID":      sourceAccount.ID.String(),
-			"DestinationAccountID": params.DestinationAccountID.String(),
-			"timestamp":            t.timeProvider.Now().Format(time.RFC3339),
-		}),
-		Status:          constants.TransactionStatusPENDING,
-		TransactionType: constants.TransactionTypeOUTBOUND,
+// buildTransferEntries assembles the balanced outgoing/incoming/fee ledger
+// rows for a transfer. Each row carries both the debit and credit account so
+// PostLedgerEntries can validate and post the whole set atomically.
+func (t *TransactionOperations) buildTransferEntries(params TransferParams, sourceAccount, destinationAccount accounts.Account, fxResult *FXConversionResult) []transactions.TransactionEntry {
+	timestamp := t.timeProvider.Now().Format(time.RFC3339)
+
+	incomingAmount := params.Amount
+	outgoingEntryType := constants.EntryTypeOutgoing
+	incomingEntryType := constants.EntryTypeIncoming
+	transferMetadata := map[string]interface{}{
+		"OperationType": "Transfer",
+		"timestamp":     timestamp,
 	}
-	pendingOutGoingTransaction, err := t.findOrCreateTransaction(ctx, pendingOutgoingTransactionParams)
-	if err != nil {
-		return nil, temporal.NewNonRetryableApplicationError(err.Error(), "error while creating pending outgoing trx", nil)
+	if fxResult != nil {
+		incomingAmount = fxResult.DestinationAmount
+		// A cross-currency transfer is recorded as a paired FX conversion
+		// transaction rather than a plain Outgoing/Incoming pair, so the
+		// conversion itself (rate, base/quote amounts, provider) is
+		// independently queryable and auditable.
+		outgoingEntryType = constants.EntryTypeFXDebit
+		incomingEntryType = constants.EntryTypeFXCredit
+		transferMetadata["FXRateProviderID"] = fxResult.ProviderID
+		transferMetadata["FXRate"] = fxResult.Rate
+		transferMetadata["FXBaseAmount"] = fxResult.SourceAmount
+		transferMetadata["FXBaseCurrency"] = fxResult.SourceCurrency
+		transferMetadata["FXQuoteAmount"] = fxResult.DestinationAmount
+		transferMetadata["FXQuoteCurrency"] = fxResult.DestinationCurrency
+		transferMetadata["FXExpiresAt"] = fxResult.ExpiresAt.Format(time.RFC3339)
 	}
-	return pendingOutGoingTransaction, nil
-}
 
-func (t *TransactionOperations) createPendingFeeTransaction(ctx context.Context, params TransferParams, sourceAccount accounts.Account) (*transactions.Transaction, error) {
-	if params.FeeAmount == nil {
-		return nil, nil
+	entries := []transactions.TransactionEntry{
+		{
+			UserID:             &sourceAccount.UserID,
+			DebitAccountID:     sourceAccount.ID,
+			CreditAccountID:    destinationAccount.ID,
+			Amount:             params.Amount,
+			CurrencyCode:       constants.CurrencyCode(sourceAccount.Currency),
+			ReferenceID:        params.SourceTransactionReferenceID,
+			EntryType:          outgoingEntryType,
+			Status:             constants.TransactionStatusPENDING,
+			MultiTransactionID: &params.MultiTransactionID,
+			Metadata:           datatypes.JSONMap(transferMetadata),
+		},
+		{
+			UserID:             &destinationAccount.UserID,
+			DebitAccountID:     sourceAccount.ID,
+			CreditAccountID:    destinationAccount.ID,
+			Amount:             incomingAmount,
+			CurrencyCode:       constants.CurrencyCode(destinationAccount.Currency),
+			ReferenceID:        params.DestinationTransactionReferenceID,
+			EntryType:          incomingEntryType,
+			Status:             constants.TransactionStatusPENDING,
+			MultiTransactionID: &params.MultiTransactionID,
+			Metadata:           datatypes.JSONMap(transferMetadata),
+		},
 	}
-	pendingOutgoingFeeTransactionParams := &transactions.Transaction{
-		UserID:       &sourceAccount.UserID,
-		Amount:       *params.FeeAmount,
-		AccountID:    sourceAccount.ID,
-		CurrencyCode: constants.CurrencyCode(sourceAccount.Currency),
-		ReferenceID:  params.FeeTransactionReferenceID,
-		Metadata: datatypes.JSONMap(map[string]interface{}{
-			"OperationType":       "Fee Transfer",
-			"LinkedTransactionID": params.FeeTransactionReferenceID.String(),
-			"LinkedAccountID":     params.SourceAccountID.String(),
-			"timestamp":           t.timeProvider.Now().Format(time.RFC3339),
-		}),
-		Status:          constants.TransactionStatusPENDING,
-		TransactionType: constants.TransactionTypeOUTGOINGFEE,
-	}
-	pendingOutGoingFeeTransaction, err := t.findOrCreateTransaction(ctx, pendingOutgoingFeeTransactionParams)
-	if err != nil {
-		return nil, temporal.NewNonRetryableApplicationError(err.Error(), "error while creating pending outgoing fee trx", nil)
-	}
-	return pendingOutGoingFeeTransaction, nil
-}
 
-func (t *TransactionOperations) createPendingIncomingTransaction(ctx context.Context, params TransferParams, destinationAccount accounts.Account) (*transactions.Transaction, error) {
-	pendingIncomingTransactionParams := &transactions.Transaction{
-		UserID:       &destinationAccount.UserID,
-		Amount:       params.Amount,
-		AccountID:    destinationAccount.ID,
-		CurrencyCode: constants.CurrencyCode(destinationAccount.Currency),
-		Metadata: datatypes.JSONMap(map[string]interface{}{
-			"OperationType":        "Transfer",
-			"LinkedTransactionID":  params.DestinationTransactionReferenceID.String(),
-			"LinkedAccountID":      params.DestinationAccountID.String(),
-			"DestinationAccountID": params.DestinationAccountID.String(),
-			"SourceAccountID":      params.SourceAccountID,
-			"timestamp":            t.timeProvider.Now().Format(time.RFC3339),
-		}),
-		ReferenceID: params.DestinationTransactionReferenceID,
-
-		Status:          constants.TransactionStatusPENDING,
-		TransactionType: constants.TransactionTypeINBOUND,
+	if params.FeeAmount != nil {
+		entries = append(entries, transactions.TransactionEntry{
+			UserID:             &sourceAccount.UserID,
+			DebitAccountID:     sourceAccount.ID,
+			CreditAccountID:    params.FeeAccountID,
+			Amount:             *params.FeeAmount,
+			CurrencyCode:       constants.CurrencyCode(sourceAccount.Currency),
+			ReferenceID:        params.FeeTransactionReferenceID,
+			EntryType:          constants.EntryTypeFee,
+			Status:             constants.TransactionStatusPENDING,
+			MultiTransactionID: &params.MultiTransactionID,
+			Metadata: datatypes.JSONMap(map[string]interface{}{
+				"OperationType": "Fee Transfer",
+				"timestamp":     timestamp,
+			}),
+		})
 	}
-	pendingIncomingTransaction, err := t.findOrCreateTransaction(ctx, pendingIncomingTransactionParams)
-	if err != nil {
-		return nil, temporal.NewNonRetryableApplicationError(err.Error(), "error while creating pending incoming trx", nil)
-	}
-	return pendingIncomingTransaction, nil
+
+	return entries
 }
 
-func (t *TransactionOperations) updateAccountBalances(ctx context.Context, sourceAcc, destinationAcc accounts.Account, params TransferParams) error {
+func (t *TransactionOperations) updateAccountBalances(ctx context.Context, sourceAcc, destinationAcc accounts.Account, params TransferParams, fxResult *FXConversionResult) error {
 	sourceAccBalanceUpdateErr := t.accountsService.UpdateBalance(ctx, sourceAcc.ID, params.Amount, constants.BalanceOperationDECREASE.String())
 	if sourceAccBalanceUpdateErr != nil {
 		return sourceAccBalanceUpdateErr
 	}
 
-	destinationAccBalanceUpdateErr := t.accountsService.UpdateBalance(ctx, destinationAcc.ID, params.Amount, constants.BalanceOperationINCREASE.String())
+	destinationAmount := params.Amount
+	if fxResult != nil {
+		destinationAmount = fxResult.DestinationAmount
+	}
+
+	destinationAccBalanceUpdateErr := t.accountsService.UpdateBalance(ctx, destinationAcc.ID, destinationAmount, constants.BalanceOperationINCREASE.String())
 	if destinationAccBalanceUpdateErr != nil {
 		return destinationAccBalanceUpdateErr
 	}
@@ -174,55 +202,28 @@ func (t *TransactionOperations) updateAccountBalances(ctx context.Context, sourc
 	return nil
 }
 
-func (t *TransactionOperations) finalizeTransactions(ctx context.Context, outgoing, incoming, fee *transactions.Transaction) (*UpdatedTransactions, error) {
-	updatedOutgoingTrx, updatedOutgoingTrxErr := t.transactionService.UpdateTransactionStatus(ctx, outgoing.ID, constants.TransactionStatusSUCCESS)
-	if updatedOutgoingTrxErr != nil {
-		return nil, updatedOutgoingTrxErr
+func (t *TransactionOperations) createTransferResult(params TransferParams, entries []transactions.TransactionEntry) *TransferResult {
+	sourceEntry := entryByType(entries, string(constants.EntryTypeOutgoing))
+	if sourceEntry == nil {
+		sourceEntry = entryByType(entries, string(constants.EntryTypeFXDebit))
 	}
 
-	updatedIncomingTrx, updatedIncomingTrxErr := t.transactionService.UpdateTransactionStatus(ctx, incoming.ID, constants.TransactionStatusSUCCESS)
-	if updatedIncomingTrxErr != nil {
-		return nil, updatedIncomingTrxErr
+	destinationEntry := entryByType(entries, string(constants.EntryTypeIncoming))
+	if destinationEntry == nil {
+		destinationEntry = entryByType(entries, string(constants.EntryTypeFXCredit))
 	}
-	var feeTrx *transactions.Transaction
 
-	if fee != nil {
-		fee.Status = constants.TransactionStatusSUCCESS
-		updatedFeeTrx, updatedFeeTrxErr := t.transactionService.UpdateTransactionStatus(ctx, fee.ID, constants.TransactionStatusSUCCESS)
-		if updatedFeeTrxErr != nil {
-			return nil, updatedFeeTrxErr
-		}
-		feeTrx = updatedFeeTrx
-	}
-
-	return &UpdatedTransactions{
-		IncomingTrx: *updatedIncomingTrx,
-		OutgoingTrx: *updatedOutgoingTrx,
-		FeeTrx:      feeTrx,
-	}, nil
-
-}
-
-func (t *TransactionOperations) createTransferResult(params TransferParams, outgoing, incoming, fee *transactions.Transaction) *TransferResult {
 	return &TransferResult{
 		SourceTransactionReferenceID:      params.SourceTransactionReferenceID,
 		DestinationTransactionReferenceID: params.DestinationTransactionReferenceID,
 		FeeTransactionReferenceID:         params.FeeTransactionReferenceID,
-		FeeTransaction:                    fee,
-		SourceTransaction:                 outgoing,
-		DestinationTransaction:            incoming,
+		MultiTransactionID:                params.MultiTransactionID,
+		SourceEntry:                       sourceEntry,
+		DestinationEntry:                  destinationEntry,
+		FeeEntry:                          entryByType(entries, string(constants.EntryTypeFee)),
 	}
 }
 
-func (t *TransactionOperations) findOrCreateTransaction(ctx context.Context, params *transactions.Transaction) (*transactions.Transaction, error) {
-	transaction, transactionErr := t.finderOrCreatorService.Call(ctx, params)
-	if transactionErr != nil {
-		return nil, transactionErr
-	}
-
-	return transaction, nil
-}
-
 func (t *TransactionOperations) validateAccount(ctx context.Context, transferAmount int, feeAmount *int, sourceAccountID uuid.UUID, destinationAccountID uuid.UUID) (*ValidAccounts, error) {
 	sourceAccount, accountErr := t.accountsService.GetAccountByID(ctx, sourceAccountID)
 	if accountErr != nil {
@@ -255,8 +256,9 @@ func (t *TransactionOperations) validateAccount(ctx context.Context, transferAmo
 		totalAmount += *feeAmount
 	}
 
-	if totalAmount > sourceAccount.Balance {
-		return nil, fmt.Errorf("insufficient balance! transfer amount: %d,  account balance: %d", totalAmount, sourceAccount.Balance)
+	availableBalance := sourceAccount.Balance - sourceAccount.ReservedAmount
+	if totalAmount > availableBalance {
+		return nil, fmt.Errorf("insufficient available balance! transfer amount: %d, available balance: %d", totalAmount, availableBalance)
 	}
 
 	return &ValidAccounts{
@@ -270,8 +272,12 @@ type ValidAccounts struct {
 	DestinationAccount *accounts.Account
 }
 
-type UpdatedTransactions struct {
-	IncomingTrx transactions.Transaction
-	OutgoingTrx transactions.Transaction
-	FeeTrx      *transactions.Transaction
+// validateFeeAccount requires a real counterparty for the fee leg whenever a
+// fee is charged, so the fee entry credits an actual fee-revenue account
+// instead of looping back to the source account it debits.
+func validateFeeAccount(params TransferParams) error {
+	if params.FeeAmount != nil && params.FeeAccountID == uuid.Nil {
+		return fmt.Errorf("fee account is required when a fee amount is set")
+	}
+	return nil
 }
@@ -0,0 +1,184 @@
+package temporalworkflows
+
+import (
+	"time"
+
+	"ulascansenturk/service/internal/connectors"
+	"ulascansenturk/service/internal/temporalworkflows/activities"
+	"ulascansenturk/service/internal/webhooks"
+
+	"github.com/google/uuid"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// ExternalTransferPollInterval is how long ExternalTransferWorkflow waits
+// between provider status checks.
+const ExternalTransferPollInterval = 5 * time.Second
+
+// ExternalTransferPollSafetyMargin is how long before the reservation's own
+// expiry ExternalTransferWorkflow gives up polling and cancels the hold
+// itself. Without it, a provider stuck in a non-terminal status would poll
+// forever and rely on ReservationSweeperWorkflow to eventually notice the
+// hold expired out from under it.
+const ExternalTransferPollSafetyMargin = 1 * time.Minute
+
+// ExternalTransferWorkflowParams describes a transfer whose destination
+// account routes to an external connector instead of another internal
+// account.
+type ExternalTransferWorkflowParams struct {
+	ConnectorID  string
+	CurrencyCode string
+	UserID       uuid.UUID
+	ReserveFunds activities.ReserveFundsParams
+}
+
+// ExternalTransferWorkflow is run as a child workflow by the transfer
+// workflow when the destination account's ConnectorID routes the outbound
+// leg to an external provider. It reserves the internal hold, dispatches the
+// transfer to the connector, polls until the provider reaches a terminal
+// status, and then either captures or cancels the held ledger entries
+// accordingly. Polling gives up and cancels the hold once the reservation is
+// close to expiring rather than continuing forever, so a provider stuck in a
+// non-terminal status can't pin funds indefinitely. A capture failure after
+// the provider has already reported success is never routed through the
+// cancel/release path, since the external leg has already moved money by
+// then; it's flagged for manual review instead.
+func ExternalTransferWorkflow(ctx workflow.Context, params ExternalTransferWorkflowParams) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy: &temporal.RetryPolicy{
+			MaximumAttempts: 5,
+		},
+	})
+
+	var ops *activities.TransactionOperations
+
+	var reservation activities.ReservationResult
+	if err := workflow.ExecuteActivity(ctx, ops.ReserveFunds, params.ReserveFunds).Get(ctx, &reservation); err != nil {
+		return err
+	}
+
+	deliverTransferWebhook(ctx, ops, params, "PENDING")
+
+	entryReferenceIDs := []uuid.UUID{
+		params.ReserveFunds.SourceTransactionReferenceID,
+		params.ReserveFunds.DestinationTransactionReferenceID,
+	}
+
+	var initiated connectors.InitiateTransferResponse
+	initiateErr := workflow.ExecuteActivity(ctx, ops.InitiateExternalTransfer, activities.InitiateExternalTransferParams{
+		ConnectorID:          params.ConnectorID,
+		ReferenceID:          params.ReserveFunds.SourceTransactionReferenceID,
+		Amount:               params.ReserveFunds.Amount,
+		CurrencyCode:         params.CurrencyCode,
+		DestinationAccountID: params.ReserveFunds.DestinationAccountID,
+		Metadata:             derefMetadata(params.ReserveFunds.Metadata),
+	}).Get(ctx, &initiated)
+	if initiateErr != nil {
+		return cancelHold(ctx, ops, params, entryReferenceIDs)
+	}
+
+	status := initiated.Status
+	providerReference := initiated.ProviderReference
+	pollDeadline := reservation.ExpiresAt.Add(-ExternalTransferPollSafetyMargin)
+
+	for !status.Terminal() {
+		if !workflow.Now(ctx).Before(pollDeadline) {
+			workflow.GetLogger(ctx).Warn("external transfer did not reach a terminal status before its poll budget expired, cancelling", "connectorID", params.ConnectorID, "providerReference", providerReference)
+			if cancelProviderErr := workflow.ExecuteActivity(ctx, ops.CancelExternalTransfer, activities.CancelExternalTransferParams{
+				ConnectorID:       params.ConnectorID,
+				ProviderReference: providerReference,
+			}).Get(ctx, nil); cancelProviderErr != nil {
+				workflow.GetLogger(ctx).Warn("provider cancel failed after poll budget expired", "error", cancelProviderErr)
+			}
+			return cancelHold(ctx, ops, params, entryReferenceIDs)
+		}
+
+		if err := workflow.Sleep(ctx, ExternalTransferPollInterval); err != nil {
+			return err
+		}
+
+		var polled connectors.StatusResponse
+		if err := workflow.ExecuteActivity(ctx, ops.PollExternalTransferStatus, activities.PollExternalTransferStatusParams{
+			ConnectorID:       params.ConnectorID,
+			ProviderReference: providerReference,
+		}).Get(ctx, &polled); err != nil {
+			return err
+		}
+		status = polled.Status
+	}
+
+	if status == connectors.StatusFailed {
+		return cancelHold(ctx, ops, params, entryReferenceIDs)
+	}
+
+	captureErr := workflow.ExecuteActivity(ctx, ops.CaptureTransfer, activities.CaptureTransferParams{
+		SourceAccountID:      params.ReserveFunds.SourceAccountID,
+		DestinationAccountID: params.ReserveFunds.DestinationAccountID,
+		Amount:               params.ReserveFunds.Amount,
+		DestinationAmount:    reservation.DestinationAmount,
+		FeeAmount:            params.ReserveFunds.FeeAmount,
+		EntryReferenceIDs:    entryReferenceIDs,
+		FXQuoteExpiresAt:     reservation.FXQuoteExpiresAt,
+	}).Get(ctx, nil)
+	if captureErr != nil {
+		// The provider already reported a terminal success by this point, so
+		// the external leg has moved money. cancelHold's CancelReservation
+		// would release the internal hold back to the source account on top
+		// of that, effectively refunding a transfer that already went out.
+		// Flag it for manual review instead of letting ReservationSweeperWorkflow
+		// auto-cancel it once the hold expires.
+		workflow.GetLogger(ctx).Error("capture failed after the external transfer already succeeded; flagging the hold for manual review instead of releasing it",
+			"connectorID", params.ConnectorID, "providerReference", providerReference, "error", captureErr)
+		if flagErr := workflow.ExecuteActivity(ctx, ops.FlagReservationForManualReview, activities.FlagReservationForManualReviewParams{
+			EntryReferenceIDs: entryReferenceIDs,
+		}).Get(ctx, nil); flagErr != nil {
+			workflow.GetLogger(ctx).Error("failed to flag reservation for manual review", "error", flagErr)
+		}
+		return captureErr
+	}
+
+	deliverTransferWebhook(ctx, ops, params, "SUCCESS")
+	return nil
+}
+
+func cancelHold(ctx workflow.Context, ops *activities.TransactionOperations, params ExternalTransferWorkflowParams, entryReferenceIDs []uuid.UUID) error {
+	cancelErr := workflow.ExecuteActivity(ctx, ops.CancelReservation, activities.CancelReservationParams{
+		SourceAccountID:   params.ReserveFunds.SourceAccountID,
+		Amount:            params.ReserveFunds.Amount,
+		FeeAmount:         params.ReserveFunds.FeeAmount,
+		EntryReferenceIDs: entryReferenceIDs,
+	}).Get(ctx, nil)
+	if cancelErr != nil {
+		return cancelErr
+	}
+
+	deliverTransferWebhook(ctx, ops, params, "FAILED")
+	return nil
+}
+
+// deliverTransferWebhook fires the outgoing-leg notification for a status
+// transition. Delivery is best-effort: a failed webhook delivery is retried
+// by its own Temporal retry policy and must never fail the transfer itself.
+func deliverTransferWebhook(ctx workflow.Context, ops *activities.TransactionOperations, params ExternalTransferWorkflowParams, status string) {
+	err := workflow.ExecuteActivity(ctx, ops.DeliverWebhook, activities.DeliverWebhookParams{
+		UserID:        params.UserID,
+		TransactionID: params.ReserveFunds.SourceTransactionReferenceID,
+		ReferenceID:   params.ReserveFunds.SourceTransactionReferenceID,
+		Direction:     webhooks.DirectionOutgoing,
+		Amount:        params.ReserveFunds.Amount,
+		CurrencyCode:  params.CurrencyCode,
+		Status:        status,
+	}).Get(ctx, nil)
+	if err != nil {
+		workflow.GetLogger(ctx).Warn("webhook delivery failed", "status", status, "error", err)
+	}
+}
+
+func derefMetadata(metadata *map[string]interface{}) map[string]interface{} {
+	if metadata == nil {
+		return nil
+	}
+	return *metadata
+}
@@ -0,0 +1,59 @@
+package temporalworkflows
+
+import (
+	"time"
+
+	"ulascansenturk/service/internal/temporalworkflows/activities"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// ReservationSweeperPollInterval is how long the sweeper sleeps between
+// sweeps before continuing-as-new.
+const ReservationSweeperPollInterval = 1 * time.Minute
+
+// ReservationSweeperWorkflow runs indefinitely (via Continue-As-New),
+// periodically cancelling any ReserveFunds hold whose workflow never called
+// CaptureTransfer or CancelReservation before its expiry. This mirrors how
+// pending payments elsewhere get transitioned to failed instead of
+// lingering: without the sweeper, a crashed or orphaned transfer workflow
+// could lock a customer's funds indefinitely. One reservation that fails to
+// cancel is logged and skipped rather than aborting the sweep, so a single
+// stuck hold can't block every other expired hold from being swept.
+func ReservationSweeperWorkflow(ctx workflow.Context) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy: &temporal.RetryPolicy{
+			MaximumAttempts: 5,
+		},
+	})
+
+	var ops *activities.TransactionOperations
+
+	var expired activities.ListExpiredReservationsResult
+	if err := workflow.ExecuteActivity(ctx, ops.ListExpiredReservations, activities.ListExpiredReservationsParams{
+		Before: workflow.Now(ctx),
+	}).Get(ctx, &expired); err != nil {
+		return err
+	}
+
+	for _, reservation := range expired.Reservations {
+		cancelErr := workflow.ExecuteActivity(ctx, ops.CancelReservation, activities.CancelReservationParams{
+			SourceAccountID:   reservation.SourceAccountID,
+			Amount:            reservation.Amount,
+			FeeAmount:         reservation.FeeAmount,
+			EntryReferenceIDs: reservation.EntryReferenceIDs,
+		}).Get(ctx, nil)
+		if cancelErr != nil {
+			workflow.GetLogger(ctx).Error("failed to cancel expired reservation, continuing sweep",
+				"sourceAccountID", reservation.SourceAccountID, "error", cancelErr)
+		}
+	}
+
+	if err := workflow.Sleep(ctx, ReservationSweeperPollInterval); err != nil {
+		return err
+	}
+
+	return workflow.NewContinueAsNewError(ctx, ReservationSweeperWorkflow)
+}
@@ -0,0 +1,65 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Deliverer POSTs signed event payloads to registered endpoints. Retries
+// across attempts are left to the caller (the DeliverWebhook activity is
+// scheduled with a Temporal exponential-backoff retry policy) so a single
+// Deliver call always represents exactly one HTTP attempt.
+type Deliverer struct {
+	Client *http.Client
+}
+
+func NewDeliverer(client *http.Client) *Deliverer {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Deliverer{Client: client}
+}
+
+func (d *Deliverer) Deliver(ctx context.Context, endpoint Endpoint, payload EventPayload) (*Attempt, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("webhooks: encoding payload: %w", err)
+	}
+
+	signature := Sign(endpoint.Secret, body)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("webhooks: building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature", signature)
+
+	attempt := &Attempt{
+		EndpointID:  endpoint.ID,
+		ReferenceID: payload.ReferenceID,
+		Status:      payload.Status,
+		Payload:     body,
+		Signature:   signature,
+		AttemptedAt: time.Now(),
+	}
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt, fmt.Errorf("webhooks: delivery request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	attempt.StatusCode = resp.StatusCode
+	if resp.StatusCode >= 300 {
+		attempt.Error = fmt.Sprintf("endpoint returned status %d", resp.StatusCode)
+		return attempt, fmt.Errorf("webhooks: %s", attempt.Error)
+	}
+
+	return attempt, nil
+}
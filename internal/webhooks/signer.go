@@ -0,0 +1,16 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Sign returns the hex-encoded HMAC-SHA256 signature of payload using
+// secret. Integrators verify it against the X-Signature header to confirm a
+// delivery really came from us.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
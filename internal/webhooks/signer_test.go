@@ -0,0 +1,21 @@
+package webhooks
+
+import "testing"
+
+func TestSign_IsDeterministicAndSecretDependent(t *testing.T) {
+	payload := []byte(`{"transaction_id":"abc"}`)
+
+	if Sign("secret-a", payload) != Sign("secret-a", payload) {
+		t.Fatal("expected the same secret and payload to produce the same signature")
+	}
+
+	if Sign("secret-a", payload) == Sign("secret-b", payload) {
+		t.Fatal("expected different secrets to produce different signatures")
+	}
+}
+
+func TestSign_IsPayloadDependent(t *testing.T) {
+	if Sign("secret", []byte("payload-a")) == Sign("secret", []byte("payload-b")) {
+		t.Fatal("expected different payloads to produce different signatures")
+	}
+}
@@ -0,0 +1,79 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EndpointStatus is the lifecycle state of a registered webhook endpoint.
+type EndpointStatus string
+
+const (
+	EndpointStatusACTIVE   EndpointStatus = "ACTIVE"
+	EndpointStatusDISABLED EndpointStatus = "DISABLED"
+)
+
+// Endpoint is a user-registered destination for transaction lifecycle
+// events, signed with Secret.
+type Endpoint struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	URL       string
+	Secret    string
+	Status    EndpointStatus
+	CreatedAt time.Time
+}
+
+// Direction identifies which leg of a transfer an event describes, matching
+// the direction-based event shape common in corporate banking webhook
+// clients.
+type Direction string
+
+const (
+	DirectionIncoming Direction = "incoming"
+	DirectionOutgoing Direction = "outgoing"
+	DirectionFee      Direction = "fee"
+)
+
+// EventPayload is the JSON body delivered to a registered endpoint.
+type EventPayload struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	ReferenceID   uuid.UUID `json:"reference_id"`
+	Direction     Direction `json:"direction"`
+	Amount        int       `json:"amount"`
+	CurrencyCode  string    `json:"currency"`
+	Status        string    `json:"status"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// Attempt records one delivery attempt so a failed or unacknowledged
+// delivery can be replayed later. ReferenceID and Status together tie it back
+// to the specific lifecycle transition it delivered: the same ReferenceID is
+// reused across a transaction's PENDING/SUCCESS/FAILED events, so Status is
+// part of the key a later retry of DeliverWebhook uses to tell whether this
+// endpoint already got this particular transition.
+type Attempt struct {
+	ID          uuid.UUID
+	EndpointID  uuid.UUID
+	ReferenceID uuid.UUID
+	Status      string
+	Payload     []byte
+	Signature   string
+	StatusCode  int
+	Error       string
+	AttemptedAt time.Time
+}
+
+// Service persists endpoints and delivery attempts.
+type Service interface {
+	FindActiveEndpointsByUser(ctx context.Context, userID uuid.UUID) ([]Endpoint, error)
+	RecordAttempt(ctx context.Context, attempt Attempt) error
+	// HasSucceeded reports whether endpointID already has a successful
+	// attempt recorded for this (referenceID, status) pair, so a retried
+	// DeliverWebhook call can skip re-notifying an endpoint for a transition
+	// it already got without also skipping a later, distinct transition that
+	// happens to share the same referenceID.
+	HasSucceeded(ctx context.Context, endpointID, referenceID uuid.UUID, status string) (bool, error)
+}